@@ -28,6 +28,40 @@ type ListenerConfig struct {
 	BrokerAddress     string
 	ListenerAddress   string
 	AdvertisedAddress string
+	Profile           string
+}
+
+// ListenerProfile overrides the default Proxy.TLS, Auth.Local and
+// Kafka.SASL settings for the bootstrap listeners naming it via
+// ListenerConfig.Profile, so that one proxy instance can front multiple
+// Kafka tenants with distinct client-auth and upstream-auth policies.
+type ListenerProfile struct {
+	TLS struct {
+		Enable                  bool
+		ListenerCertFile        string
+		ListenerKeyFile         string
+		ListenerKeyPassword     string
+		ListenerCAChainCertFile string
+		ClientCert              struct {
+			Subjects []string
+		}
+	}
+	Auth struct {
+		Local struct {
+			Enable  bool
+			Command string
+		}
+	}
+	Kafka struct {
+		SASL struct {
+			Enable         bool
+			Username       string
+			Password       string
+			Method         string
+			JaasConfigFile string
+			AWSConfig      AWSConfig
+		}
+	}
 }
 
 type DialAddressMapping struct {
@@ -52,6 +86,41 @@ type AWSConfig struct {
 	Profile        string
 	RoleArn        string
 	IdentityLookup bool
+
+	AccessKeyID          string
+	SecretAccessKey      string
+	SessionToken         string
+	STSEndpoint          string
+	RoleSessionName      string
+	ExternalID           string
+	WebIdentityTokenFile string
+	CredentialsRefresh   time.Duration
+}
+
+type AdminConfig struct {
+	AutoCreateTopics         bool
+	DefaultPartitions        int32
+	DefaultReplicationFactor int16
+	MetadataRefreshInterval  time.Duration
+	TopicConfigOverrides     map[string]map[string]string
+}
+
+type SSHConfig struct {
+	Enable bool
+
+	Host string
+	User string
+
+	Password       string
+	PrivateKey     string
+	PrivateKeyPath string
+	Passphrase     string
+
+	KnownHostsFile        string
+	InsecureIgnoreHostKey bool
+
+	KeepAlive time.Duration
+	Timeout   time.Duration
 }
 
 type Config struct {
@@ -81,6 +150,7 @@ type Config struct {
 		DefaultListenerIP         string
 		BootstrapServers          []ListenerConfig
 		ExternalServers           []ListenerConfig
+		Profiles                  map[string]ListenerProfile
 		DeterministicListeners    bool
 		DialAddressMappings       []DialAddressMapping
 		DisableDynamicListeners   bool
@@ -184,6 +254,8 @@ type Config struct {
 		Producer struct {
 			Acks0Disabled bool
 		}
+		Admin AdminConfig
+		SSH   SSHConfig
 	}
 	ForwardProxy struct {
 		Url string
@@ -248,8 +320,8 @@ func getListenerConfigs(serversMapping []string) ([]ListenerConfig, error) {
 	listenerConfigs := make([]ListenerConfig, 0, len(serversMapping))
 	for _, v := range serversMapping {
 		pair := strings.Split(v, ",")
-		if len(pair) != 2 && len(pair) != 3 {
-			return nil, errors.New("server-mapping must be in form 'remotehost:remoteport,localhost:localport(,advhost:advport)'")
+		if len(pair) < 2 || len(pair) > 4 {
+			return nil, errors.New("server-mapping must be in form 'remotehost:remoteport,localhost:localport(,advhost:advport(,profile))'")
 		}
 		remoteHost, remotePort, err := util.SplitHostPort(pair[0])
 		if err != nil {
@@ -260,17 +332,21 @@ func getListenerConfigs(serversMapping []string) ([]ListenerConfig, error) {
 			return nil, err
 		}
 		advertisedHost, advertisedPort := localHost, localPort
-		if len(pair) == 3 {
-			advertisedHost, advertisedPort, err = util.SplitHostPort(pair[2])
-			if err != nil {
+		profile := ""
+		if len(pair) >= 3 {
+			if advertisedHost, advertisedPort, err = util.SplitHostPort(pair[2]); err != nil {
 				return nil, err
 			}
 		}
+		if len(pair) == 4 {
+			profile = pair[3]
+		}
 
 		listenerConfig := ListenerConfig{
 			BrokerAddress:     net.JoinHostPort(remoteHost, fmt.Sprint(remotePort)),
 			ListenerAddress:   net.JoinHostPort(localHost, fmt.Sprint(localPort)),
-			AdvertisedAddress: net.JoinHostPort(advertisedHost, fmt.Sprint(advertisedPort))}
+			AdvertisedAddress: net.JoinHostPort(advertisedHost, fmt.Sprint(advertisedPort)),
+			Profile:           profile}
 		listenerConfigs = append(listenerConfigs, listenerConfig)
 	}
 	return listenerConfigs, nil
@@ -299,6 +375,19 @@ func NewConfig() *Config {
 	return c
 }
 
+func validateAWSConfig(path string, cfg AWSConfig) error {
+	if cfg.Region == "" {
+		return errors.Errorf("%s.Region is required when SASL Method AWS_MSK_IAM is used", path)
+	}
+	if (cfg.WebIdentityTokenFile != "" || cfg.ExternalID != "") && cfg.RoleArn == "" {
+		return errors.Errorf("%s.RoleArn is required when WebIdentityTokenFile or ExternalID is set", path)
+	}
+	if cfg.CredentialsRefresh < 0 {
+		return errors.Errorf("%s.CredentialsRefresh must be greater or equal 0", path)
+	}
+	return nil
+}
+
 func (c *Config) Validate() error {
 	if c.Kafka.SASL.Enable {
 		if c.Kafka.SASL.Plugin.Enable {
@@ -335,7 +424,9 @@ func (c *Config) Validate() error {
 					return errors.New("GSSAPI Realm must not be empty")
 				}
 			} else if c.Kafka.SASL.Method == "AWS_MSK_IAM" {
-
+				if err := validateAWSConfig("Kafka.SASL.AWSConfig", c.Kafka.SASL.AWSConfig); err != nil {
+					return err
+				}
 			} else {
 				if c.Kafka.SASL.Username == "" || c.Kafka.SASL.Password == "" {
 					return errors.New("SASL.Username and SASL.Password are required when SASL is enabled and plugin is not used")
@@ -363,6 +454,36 @@ func (c *Config) Validate() error {
 	if c.Kafka.MaxOpenRequests < 1 {
 		return errors.New("MaxOpenRequests must be greater than 0")
 	}
+	if c.Kafka.Admin.AutoCreateTopics && c.Kafka.Admin.DefaultReplicationFactor < 1 {
+		return errors.New("Kafka.Admin.DefaultReplicationFactor must be greater than 0")
+	}
+	// A zero MetadataRefreshInterval means "use the manager's default"; any
+	// explicit value below that still has to be at least 1m, regardless of
+	// AutoCreateTopics, because the refresh ticker and the cache's
+	// min-interval burst guard both run whenever the manager is constructed.
+	if c.Kafka.Admin.MetadataRefreshInterval != 0 && c.Kafka.Admin.MetadataRefreshInterval < time.Minute {
+		return errors.New("Kafka.Admin.MetadataRefreshInterval must be at least 1m")
+	}
+	if c.Kafka.SSH.Enable {
+		if c.ForwardProxy.Url != "" {
+			return errors.New("Kafka.SSH and ForwardProxy.Url are mutually exclusive")
+		}
+		if c.Kafka.SSH.Host == "" {
+			return errors.New("Kafka.SSH.Host is required when Kafka.SSH is enabled")
+		}
+		if _, _, err := net.SplitHostPort(c.Kafka.SSH.Host); err != nil {
+			return errors.New("Kafka.SSH.Host must include a port")
+		}
+		if c.Kafka.SSH.User == "" {
+			return errors.New("Kafka.SSH.User is required when Kafka.SSH is enabled")
+		}
+		if c.Kafka.SSH.Password == "" && c.Kafka.SSH.PrivateKey == "" && c.Kafka.SSH.PrivateKeyPath == "" {
+			return errors.New("one of Kafka.SSH.Password, Kafka.SSH.PrivateKey or Kafka.SSH.PrivateKeyPath is required when Kafka.SSH is enabled")
+		}
+		if c.Kafka.SSH.KnownHostsFile == "" && !c.Kafka.SSH.InsecureIgnoreHostKey {
+			return errors.New("Kafka.SSH.KnownHostsFile is required when Kafka.SSH is enabled, unless Kafka.SSH.InsecureIgnoreHostKey is set")
+		}
+	}
 	// proxy
 	if len(c.Proxy.BootstrapServers) == 0 {
 		return errors.New("list of bootstrap-server-mapping must not be empty")
@@ -385,6 +506,28 @@ func (c *Config) Validate() error {
 	if c.Proxy.TLS.Enable && (c.Proxy.TLS.ListenerKeyFile == "" || c.Proxy.TLS.ListenerCertFile == "") {
 		return errors.New("ListenerKeyFile and ListenerCertFile are required when Proxy TLS is enabled")
 	}
+	for _, listenerConfig := range c.Proxy.BootstrapServers {
+		if listenerConfig.Profile != "" {
+			if _, ok := c.Proxy.Profiles[listenerConfig.Profile]; !ok {
+				return errors.Errorf("Proxy.Profiles has no entry for profile %q referenced by bootstrap-server-mapping", listenerConfig.Profile)
+			}
+		}
+	}
+	for name, profile := range c.Proxy.Profiles {
+		if profile.TLS.Enable && (profile.TLS.ListenerKeyFile == "" || profile.TLS.ListenerCertFile == "") {
+			return errors.Errorf("Profiles[%s]: ListenerKeyFile and ListenerCertFile are required when TLS is enabled", name)
+		}
+		if profile.Auth.Local.Enable && profile.Auth.Local.Command == "" {
+			return errors.Errorf("Profiles[%s]: Auth.Local.Command is required when Auth.Local is enabled", name)
+		}
+		if profile.Kafka.SASL.Enable && profile.Kafka.SASL.Method == "AWS_MSK_IAM" {
+			if err := validateAWSConfig(fmt.Sprintf("Profiles[%s].Kafka.SASL.AWSConfig", name), profile.Kafka.SASL.AWSConfig); err != nil {
+				return err
+			}
+		} else if profile.Kafka.SASL.Enable && (profile.Kafka.SASL.Username == "" || profile.Kafka.SASL.Password == "") {
+			return errors.Errorf("Profiles[%s]: Kafka.SASL.Username and Kafka.SASL.Password are required when Kafka.SASL is enabled", name)
+		}
+	}
 	if c.Kafka.TLS.SameClientCertEnable && (!c.Kafka.TLS.Enable || c.Kafka.TLS.ClientCertFile == "" || !c.Proxy.TLS.Enable) {
 		return errors.New("ClientCertFile is required on Kafka TLS and TLS must be enabled on both Proxy and Kafka connections when SameClientCertEnable is enabled")
 	}
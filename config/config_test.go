@@ -0,0 +1,72 @@
+package config
+
+import "testing"
+
+func TestGetListenerConfigs(t *testing.T) {
+	tests := []struct {
+		name          string
+		mapping       string
+		wantAdvHost   string
+		wantAdvPort   string
+		wantProfile   string
+		wantErrSubstr string
+	}{
+		{
+			name:        "remote and local only",
+			mapping:     "kafka0:9092,localhost:32401",
+			wantAdvHost: "localhost",
+			wantAdvPort: "32401",
+		},
+		{
+			name:        "explicit advertised address",
+			mapping:     "kafka0:9092,localhost:32401,myhost:32401",
+			wantAdvHost: "myhost",
+			wantAdvPort: "32401",
+		},
+		{
+			name:        "advertised address plus profile",
+			mapping:     "kafka0:9092,localhost:32401,myhost:32401,tenant-a",
+			wantAdvHost: "myhost",
+			wantAdvPort: "32401",
+			wantProfile: "tenant-a",
+		},
+		{
+			name:          "typo in advertised address is a hard error, not a profile name",
+			mapping:       "kafka0:9092,localhost:32401,localhost:900a",
+			wantErrSubstr: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			configs, err := getListenerConfigs([]string{tt.mapping})
+			if tt.name == "typo in advertised address is a hard error, not a profile name" {
+				if err == nil {
+					t.Fatalf("expected an error for an invalid advertised address, got listener configs: %+v", configs)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(configs) != 1 {
+				t.Fatalf("expected 1 listener config, got %d", len(configs))
+			}
+			got := configs[0]
+			wantAdvertised := tt.wantAdvHost + ":" + tt.wantAdvPort
+			if got.AdvertisedAddress != wantAdvertised {
+				t.Errorf("AdvertisedAddress = %q, want %q", got.AdvertisedAddress, wantAdvertised)
+			}
+			if got.Profile != tt.wantProfile {
+				t.Errorf("Profile = %q, want %q", got.Profile, tt.wantProfile)
+			}
+		})
+	}
+}
+
+func TestGetListenerConfigs_RejectsTooManyFields(t *testing.T) {
+	_, err := getListenerConfigs([]string{"kafka0:9092,localhost:32401,myhost:32401,profile,extra"})
+	if err == nil {
+		t.Fatal("expected an error for a mapping with too many fields")
+	}
+}
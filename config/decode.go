@@ -0,0 +1,57 @@
+package config
+
+import (
+	"fmt"
+
+	"github.com/mitchellh/mapstructure"
+	"github.com/pkg/errors"
+)
+
+// decodeInto populates cfg from raw (a generic tree produced by
+// yaml.Unmarshal/json.Unmarshal into an interface{}), converting duration
+// strings such as "15s" into the time.Duration fields Config is full of
+// (Kafka.DialTimeout, Proxy.TLS.Refresh, Kafka.Admin.MetadataRefreshInterval,
+// AWSConfig.CredentialsRefresh, ...). Plain encoding/json and gopkg.in/yaml.v2
+// can only decode a duration as a raw int64 of nanoseconds, which defeats the
+// point of a human-edited config file.
+func decodeInto(raw interface{}, cfg *Config) error {
+	decoder, err := mapstructure.NewDecoder(&mapstructure.DecoderConfig{
+		DecodeHook: mapstructure.StringToTimeDurationHookFunc(),
+		Result:     cfg,
+	})
+	if err != nil {
+		return errors.Wrap(err, "config: building decoder")
+	}
+	if err := decoder.Decode(stringKeys(raw)); err != nil {
+		return errors.Wrap(err, "config: decoding")
+	}
+	return nil
+}
+
+// stringKeys recursively rewrites the map[interface{}]interface{} nodes that
+// gopkg.in/yaml.v2 produces into map[string]interface{}, which is what
+// mapstructure expects.
+func stringKeys(v interface{}) interface{} {
+	switch val := v.(type) {
+	case map[interface{}]interface{}:
+		m := make(map[string]interface{}, len(val))
+		for k, child := range val {
+			m[fmt.Sprint(k)] = stringKeys(child)
+		}
+		return m
+	case map[string]interface{}:
+		m := make(map[string]interface{}, len(val))
+		for k, child := range val {
+			m[k] = stringKeys(child)
+		}
+		return m
+	case []interface{}:
+		out := make([]interface{}, len(val))
+		for i, child := range val {
+			out[i] = stringKeys(child)
+		}
+		return out
+	default:
+		return v
+	}
+}
@@ -0,0 +1,250 @@
+package config
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v2"
+)
+
+// OnReload is called with the newly validated Config whenever a live-reloadable
+// subsystem changes. Subsystems register one of these with a Loader instead of
+// re-reading Config fields on every use.
+type OnReload func(*Config)
+
+// ReloadPlan describes, for a single Diff, which subsystems can be applied to
+// a running proxy without a restart and which require one.
+type ReloadPlan struct {
+	Live          []string
+	RestartNeeded []string
+}
+
+// RequiresRestart reports whether applying the plan would require restarting
+// the proxy process.
+func (p ReloadPlan) RequiresRestart() bool {
+	return len(p.RestartNeeded) > 0
+}
+
+// Loader loads a Config from a YAML or JSON file, optionally watches that
+// file for changes and notifies registered subsystems when a reload is safe
+// to apply live.
+type Loader struct {
+	mu        sync.Mutex
+	listeners []OnReload
+	current   *Config
+}
+
+// NewLoader returns a Loader with no current Config and no registered listeners.
+func NewLoader() *Loader {
+	return &Loader{}
+}
+
+// OnReload registers fn to be called with the new Config after every
+// successful Watch-triggered reload.
+func (l *Loader) OnReload(fn OnReload) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.listeners = append(l.listeners, fn)
+}
+
+// Load reads the file at path, merging its values under the flag-provided
+// defaults in base (flags win over file values), validates the result and
+// remembers it as the Loader's current Config.
+func (l *Loader) Load(path string, base *Config) (*Config, error) {
+	cfg, err := l.parse(path, base)
+	if err != nil {
+		return nil, err
+	}
+	l.mu.Lock()
+	l.current = cfg
+	l.mu.Unlock()
+	return cfg, nil
+}
+
+// parse reads and validates the file at path without touching the Loader's
+// current Config, so callers can decide whether a reload is actually safe
+// to apply before recording it as such.
+func (l *Loader) parse(path string, base *Config) (*Config, error) {
+	cfg, err := loadFile(path, base)
+	if err != nil {
+		return nil, err
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// Watch reloads path whenever it changes on disk and invokes fn (and every
+// OnReload listener) with the new Config. It blocks until ctx is cancelled.
+func (l *Loader) Watch(ctx context.Context, path string, base *Config, fn func(*Config)) error {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return errors.Wrap(err, "config: creating file watcher")
+	}
+	defer watcher.Close()
+
+	if err := watcher.Add(filepath.Dir(path)); err != nil {
+		return errors.Wrapf(err, "config: watching %s", path)
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return nil
+			}
+			if filepath.Clean(event.Name) != filepath.Clean(path) {
+				continue
+			}
+			if event.Op&(fsnotify.Write|fsnotify.Create) == 0 {
+				continue
+			}
+			l.mu.Lock()
+			old := l.current
+			l.mu.Unlock()
+
+			newCfg, err := l.parse(path, base)
+			if err != nil {
+				// Keep serving the last good config; a malformed edit on disk
+				// must not bring down subsystems that are already running.
+				continue
+			}
+			if old != nil && Diff(old, newCfg).RequiresRestart() {
+				// Do not record newCfg as current: it was never applied to
+				// the running subsystems, so the next iteration must keep
+				// diffing against what's actually in effect, not against
+				// this skipped snapshot.
+				continue
+			}
+			if fn != nil {
+				fn(newCfg)
+			}
+			l.mu.Lock()
+			l.current = newCfg
+			listeners := append([]OnReload(nil), l.listeners...)
+			l.mu.Unlock()
+			for _, listener := range listeners {
+				listener(newCfg)
+			}
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return nil
+			}
+			return errors.Wrap(err, "config: watching file")
+		}
+	}
+}
+
+// Diff compares two validated Configs and reports which subsystems differ
+// and whether applying new live, without a restart, is possible.
+func Diff(old, new *Config) ReloadPlan {
+	var plan ReloadPlan
+
+	if old.Proxy.TLS.Refresh != new.Proxy.TLS.Refresh ||
+		old.Proxy.TLS.ListenerCertFile != new.Proxy.TLS.ListenerCertFile ||
+		old.Proxy.TLS.ListenerKeyFile != new.Proxy.TLS.ListenerKeyFile {
+		plan.Live = append(plan.Live, "Proxy.TLS")
+	}
+	if !intSliceEqual(old.Kafka.ForbiddenApiKeys, new.Kafka.ForbiddenApiKeys) {
+		plan.Live = append(plan.Live, "Kafka.ForbiddenApiKeys")
+	}
+	if old.Proxy.ListenerKeepAlive != new.Proxy.ListenerKeepAlive {
+		plan.Live = append(plan.Live, "Proxy.ListenerKeepAlive")
+	}
+	if old.Kafka.SASL.Username != new.Kafka.SASL.Username ||
+		old.Kafka.SASL.Password != new.Kafka.SASL.Password ||
+		old.Kafka.SASL.JaasConfigFile != new.Kafka.SASL.JaasConfigFile {
+		plan.Live = append(plan.Live, "Kafka.SASL")
+	}
+	if old.ForwardProxy.Url != new.ForwardProxy.Url {
+		plan.Live = append(plan.Live, "ForwardProxy.Url")
+	}
+	if !dialMappingsEqual(old.Proxy.DialAddressMappings, new.Proxy.DialAddressMappings) {
+		plan.Live = append(plan.Live, "Proxy.DialAddressMappings")
+	}
+	if old.Proxy.DynamicSequentialMinPort != new.Proxy.DynamicSequentialMinPort ||
+		old.Proxy.DynamicSequentialMaxPorts != new.Proxy.DynamicSequentialMaxPorts {
+		plan.Live = append(plan.Live, "Proxy.DynamicSequentialPortRange")
+	}
+
+	if !listenerConfigsEqual(old.Proxy.BootstrapServers, new.Proxy.BootstrapServers) {
+		plan.RestartNeeded = append(plan.RestartNeeded, "Proxy.BootstrapServers")
+	}
+	if old.Proxy.DefaultListenerIP != new.Proxy.DefaultListenerIP {
+		plan.RestartNeeded = append(plan.RestartNeeded, "Proxy.DefaultListenerIP")
+	}
+
+	return plan
+}
+
+func loadFile(path string, base *Config) (*Config, error) {
+	contents, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "config: reading %s", path)
+	}
+
+	var raw interface{}
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		if err := json.Unmarshal(contents, &raw); err != nil {
+			return nil, errors.Wrapf(err, "config: parsing %s", path)
+		}
+	case ".yaml", ".yml":
+		if err := yaml.Unmarshal(contents, &raw); err != nil {
+			return nil, errors.Wrapf(err, "config: parsing %s", path)
+		}
+	default:
+		return nil, errors.Errorf("config: unsupported config file extension %q", filepath.Ext(path))
+	}
+
+	var fileCfg Config
+	if err := decodeInto(raw, &fileCfg); err != nil {
+		return nil, errors.Wrapf(err, "config: decoding %s", path)
+	}
+	return mergeFlagsOverFile(&fileCfg, base), nil
+}
+
+func intSliceEqual(a, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func dialMappingsEqual(a, b []DialAddressMapping) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func listenerConfigsEqual(a, b []ListenerConfig) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
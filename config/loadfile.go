@@ -0,0 +1,112 @@
+package config
+
+import (
+	"io/ioutil"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v2"
+)
+
+// envVarPattern matches ${ENV:VAR} and ${ENV:VAR:-default}.
+var envVarPattern = regexp.MustCompile(`\$\{ENV:([A-Za-z_][A-Za-z0-9_]*)(:-([^}]*))?\}`)
+
+// fileRefPattern matches ${FILE:/path/to/file}.
+var fileRefPattern = regexp.MustCompile(`\$\{FILE:([^}]+)\}`)
+
+// LoadFile reads the YAML document at path, expands ${ENV:VAR} /
+// ${ENV:VAR:-default} placeholders from the process environment and
+// ${FILE:/path} placeholders with the trimmed contents of the referenced
+// file (so a Kubernetes-mounted secret can provide e.g. Kafka.SASL.Password
+// without it appearing on the command line or in the YAML itself), merges
+// the result under the flag-populated base config (flags win) and validates
+// it before returning.
+func LoadFile(path string, base *Config) (*Config, error) {
+	contents, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "config: reading %s", path)
+	}
+
+	var raw interface{}
+	if err := yaml.Unmarshal(contents, &raw); err != nil {
+		return nil, errors.Wrapf(err, "config: parsing %s", path)
+	}
+	expanded, err := expandValue(raw)
+	if err != nil {
+		return nil, errors.Wrapf(err, "config: expanding %s", path)
+	}
+
+	var fileCfg Config
+	if err := decodeInto(expanded, &fileCfg); err != nil {
+		return nil, errors.Wrapf(err, "config: decoding %s", path)
+	}
+	cfg := mergeFlagsOverFile(&fileCfg, base)
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+func expandValue(v interface{}) (interface{}, error) {
+	switch val := v.(type) {
+	case string:
+		return expandString(val)
+	case map[interface{}]interface{}:
+		result := make(map[interface{}]interface{}, len(val))
+		for k, child := range val {
+			expanded, err := expandValue(child)
+			if err != nil {
+				return nil, err
+			}
+			result[k] = expanded
+		}
+		return result, nil
+	case []interface{}:
+		result := make([]interface{}, len(val))
+		for i, child := range val {
+			expanded, err := expandValue(child)
+			if err != nil {
+				return nil, err
+			}
+			result[i] = expanded
+		}
+		return result, nil
+	default:
+		return v, nil
+	}
+}
+
+func expandString(s string) (string, error) {
+	var expandErr error
+	s = fileRefPattern.ReplaceAllStringFunc(s, func(match string) string {
+		path := fileRefPattern.FindStringSubmatch(match)[1]
+		contents, err := ioutil.ReadFile(path)
+		if err != nil {
+			expandErr = errors.Wrapf(err, "config: reading secret file %s", path)
+			return match
+		}
+		return strings.TrimSpace(string(contents))
+	})
+	if expandErr != nil {
+		return "", expandErr
+	}
+
+	s = envVarPattern.ReplaceAllStringFunc(s, func(match string) string {
+		groups := envVarPattern.FindStringSubmatch(match)
+		name, hasDefault, def := groups[1], groups[2] != "", groups[3]
+		if value, ok := os.LookupEnv(name); ok {
+			return value
+		}
+		if hasDefault {
+			return def
+		}
+		expandErr = errors.Errorf("config: environment variable %s is not set and no default was given", name)
+		return match
+	})
+	if expandErr != nil {
+		return "", expandErr
+	}
+	return s, nil
+}
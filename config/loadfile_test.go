@@ -0,0 +1,68 @@
+package config
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadFile_FileValuesSurviveDefaultBase(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "kafka-proxy.yaml")
+	yamlDoc := `
+Kafka:
+  ClientID: my-client
+  ForbiddenApiKeys:
+    - 20
+    - 37
+Proxy:
+  BootstrapServers:
+    - BrokerAddress: kafka0:9092
+      ListenerAddress: localhost:32401
+      AdvertisedAddress: localhost:32401
+`
+	if err := ioutil.WriteFile(path, []byte(yamlDoc), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := LoadFile(path, NewConfig())
+	if err != nil {
+		t.Fatalf("LoadFile: %v", err)
+	}
+	if cfg.Kafka.ClientID != "my-client" {
+		t.Errorf("Kafka.ClientID = %q, want %q", cfg.Kafka.ClientID, "my-client")
+	}
+	if len(cfg.Kafka.ForbiddenApiKeys) != 2 || cfg.Kafka.ForbiddenApiKeys[0] != 20 || cfg.Kafka.ForbiddenApiKeys[1] != 37 {
+		t.Errorf("Kafka.ForbiddenApiKeys = %v, want [20 37]", cfg.Kafka.ForbiddenApiKeys)
+	}
+}
+
+func TestLoadFile_EnvVarExpansion(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "kafka-proxy.yaml")
+
+	os.Setenv("KAFKA_PROXY_TEST_CLIENT_ID", "env-client")
+	defer os.Unsetenv("KAFKA_PROXY_TEST_CLIENT_ID")
+
+	yamlDoc := `
+Kafka:
+  ClientID: ${ENV:KAFKA_PROXY_TEST_CLIENT_ID}
+Proxy:
+  BootstrapServers:
+    - BrokerAddress: kafka0:9092
+      ListenerAddress: localhost:32401
+      AdvertisedAddress: localhost:32401
+`
+	if err := ioutil.WriteFile(path, []byte(yamlDoc), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := LoadFile(path, NewConfig())
+	if err != nil {
+		t.Fatalf("LoadFile: %v", err)
+	}
+	if cfg.Kafka.ClientID != "env-client" {
+		t.Errorf("Kafka.ClientID = %q, want %q", cfg.Kafka.ClientID, "env-client")
+	}
+}
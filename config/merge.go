@@ -0,0 +1,41 @@
+package config
+
+import "reflect"
+
+// mergeFlagsOverFile returns a Config built from fileCfg (the values parsed
+// from a config file) with every field base (the flag-populated Config)
+// holds *explicitly away from NewConfig()'s built-in default* overlaid on
+// top, so that a flag actually passed on the command line always wins over
+// a file value for the same field. Comparing against the default, rather
+// than against the field's Go zero value, matters because NewConfig()
+// itself sets plenty of non-zero defaults (Kafka.ClientID, Kafka.DialTimeout,
+// Kafka.ForbiddenApiKeys, ...) that must not be mistaken for an explicit
+// override - otherwise those defaults would always win and the
+// corresponding field could never be set from the file. A field the file
+// leaves at its Go zero value takes base's value, so NewConfig()'s defaults
+// still apply to everything the file does not set; as a consequence, an
+// explicit zero in the file cannot override a non-zero default.
+func mergeFlagsOverFile(fileCfg, base *Config) *Config {
+	result := *fileCfg
+	defaults := NewConfig()
+	overlayExplicit(reflect.ValueOf(&result).Elem(), reflect.ValueOf(*base), reflect.ValueOf(*defaults))
+	return &result
+}
+
+func overlayExplicit(dst, overlay, defaults reflect.Value) {
+	for i := 0; i < dst.NumField(); i++ {
+		dstField := dst.Field(i)
+		overlayField := overlay.Field(i)
+		defaultField := defaults.Field(i)
+		if !dstField.CanSet() {
+			continue
+		}
+		if dstField.Kind() == reflect.Struct {
+			overlayExplicit(dstField, overlayField, defaultField)
+			continue
+		}
+		if dstField.IsZero() || !reflect.DeepEqual(overlayField.Interface(), defaultField.Interface()) {
+			dstField.Set(overlayField)
+		}
+	}
+}
@@ -0,0 +1,43 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestMergeFlagsOverFile_DefaultsDoNotClobberFileValues(t *testing.T) {
+	base := NewConfig()
+
+	fileCfg := &Config{}
+	fileCfg.Kafka.ClientID = "my-client"
+	fileCfg.Kafka.ForbiddenApiKeys = []int{20, 37}
+
+	merged := mergeFlagsOverFile(fileCfg, base)
+
+	if merged.Kafka.ClientID != "my-client" {
+		t.Errorf("Kafka.ClientID = %q, want %q (file value clobbered by NewConfig() default)", merged.Kafka.ClientID, "my-client")
+	}
+	if !reflect.DeepEqual(merged.Kafka.ForbiddenApiKeys, []int{20, 37}) {
+		t.Errorf("Kafka.ForbiddenApiKeys = %v, want [20 37] (file value clobbered by NewConfig() default)", merged.Kafka.ForbiddenApiKeys)
+	}
+}
+
+func TestMergeFlagsOverFile_ExplicitFlagWinsOverFile(t *testing.T) {
+	base := NewConfig()
+	base.Kafka.ClientID = "flag-client"
+	base.Kafka.DialTimeout = 5 * time.Second
+
+	fileCfg := &Config{}
+	fileCfg.Kafka.ClientID = "file-client"
+	fileCfg.Kafka.DialTimeout = 20 * time.Second
+
+	merged := mergeFlagsOverFile(fileCfg, base)
+
+	if merged.Kafka.ClientID != "flag-client" {
+		t.Errorf("Kafka.ClientID = %q, want %q (explicit flag should win)", merged.Kafka.ClientID, "flag-client")
+	}
+	if merged.Kafka.DialTimeout != 5*time.Second {
+		t.Errorf("Kafka.DialTimeout = %v, want 5s (explicit flag should win)", merged.Kafka.DialTimeout)
+	}
+}
@@ -0,0 +1,140 @@
+package awsmskiam
+
+import (
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/credentials/ec2rolecreds"
+	"github.com/aws/aws-sdk-go/aws/credentials/stscreds"
+	"github.com/aws/aws-sdk-go/aws/ec2metadata"
+	"github.com/aws/aws-sdk-go/aws/session"
+	v4 "github.com/aws/aws-sdk-go/aws/signer/v4"
+	"github.com/aws/aws-sdk-go/service/sts"
+	"github.com/grepplabs/kafka-proxy/config"
+	"github.com/pkg/errors"
+)
+
+const defaultCredentialsRefresh = 5 * time.Minute
+
+// SignerResolver builds and periodically refreshes the sigv4 signer used to
+// sign the AWS_MSK_IAM SASL handshake, so that long-running proxy processes
+// keep working after temporary credentials (assumed role, web identity,
+// instance profile) expire.
+type SignerResolver struct {
+	cfg config.AWSConfig
+
+	mu      sync.RWMutex
+	signer  *v4.Signer
+	region  string
+	stop    chan struct{}
+	stopped bool
+}
+
+// NewSignerResolver resolves an initial credential chain and starts a
+// background refresh loop according to cfg.CredentialsRefresh.
+func NewSignerResolver(cfg config.AWSConfig) (*SignerResolver, error) {
+	r := &SignerResolver{cfg: cfg, region: cfg.Region, stop: make(chan struct{})}
+	if err := r.refresh(); err != nil {
+		return nil, err
+	}
+	go r.refreshLoop()
+	return r, nil
+}
+
+// Signer returns the current sigv4 signer. Safe for concurrent use.
+func (r *SignerResolver) Signer() *v4.Signer {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.signer
+}
+
+// Close stops the background refresh loop.
+func (r *SignerResolver) Close() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if !r.stopped {
+		r.stopped = true
+		close(r.stop)
+	}
+}
+
+func (r *SignerResolver) refreshLoop() {
+	interval := r.cfg.CredentialsRefresh
+	if interval <= 0 {
+		interval = defaultCredentialsRefresh
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-r.stop:
+			return
+		case <-ticker.C:
+			// Best effort: keep serving the previous signer if a refresh
+			// fails transiently (e.g. STS throttling).
+			_ = r.refresh()
+		}
+	}
+}
+
+func (r *SignerResolver) refresh() error {
+	creds, err := credentialsChain(r.cfg)
+	if err != nil {
+		return err
+	}
+	signer := v4.NewSigner(creds)
+
+	r.mu.Lock()
+	r.signer = signer
+	r.mu.Unlock()
+	return nil
+}
+
+// credentialsChain picks the base credentials source in priority order:
+// static keys, then web identity, then named profile, then EC2/EKS instance
+// metadata. Only the first configured source is used; the others are not
+// tried as fallbacks if it fails. The base is wrapped in an AssumeRole
+// provider when AWSConfig.RoleArn is set and web identity is not used.
+func credentialsChain(cfg config.AWSConfig) (*credentials.Credentials, error) {
+	sess, err := session.NewSession(&aws.Config{Region: aws.String(cfg.Region)})
+	if err != nil {
+		return nil, errors.Wrap(err, "awsmskiam: creating AWS session")
+	}
+
+	var base *credentials.Credentials
+	switch {
+	case cfg.AccessKeyID != "" && cfg.SecretAccessKey != "":
+		base = credentials.NewStaticCredentials(cfg.AccessKeyID, cfg.SecretAccessKey, cfg.SessionToken)
+	case cfg.WebIdentityTokenFile != "":
+		stsClient := sts.New(sess, stsEndpointConfig(cfg))
+		base = credentials.NewCredentials(stscreds.NewWebIdentityRoleProvider(stsClient, cfg.RoleArn, cfg.RoleSessionName, cfg.WebIdentityTokenFile))
+	case cfg.Profile != "":
+		base = credentials.NewSharedCredentials("", cfg.Profile)
+	default:
+		base = credentials.NewCredentials(ec2rolecreds.NewProviderWithOptions(ec2metadata.New(sess), func(o *ec2rolecreds.EC2RoleProviderOptions) {}))
+	}
+
+	if cfg.RoleArn == "" || cfg.WebIdentityTokenFile != "" {
+		// Web identity assumption already targets RoleArn directly.
+		return base, nil
+	}
+
+	stsClient := sts.New(sess, stsEndpointConfig(cfg), &aws.Config{Credentials: base})
+	return stscreds.NewCredentialsWithClient(stsClient, cfg.RoleArn, func(p *stscreds.AssumeRoleProvider) {
+		if cfg.RoleSessionName != "" {
+			p.RoleSessionName = cfg.RoleSessionName
+		}
+		if cfg.ExternalID != "" {
+			p.ExternalID = aws.String(cfg.ExternalID)
+		}
+	}), nil
+}
+
+func stsEndpointConfig(cfg config.AWSConfig) *aws.Config {
+	if cfg.STSEndpoint == "" {
+		return &aws.Config{}
+	}
+	return &aws.Config{Endpoint: aws.String(cfg.STSEndpoint)}
+}
@@ -0,0 +1,161 @@
+package sshdialer
+
+import (
+	"context"
+	"io/ioutil"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/grepplabs/kafka-proxy/config"
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/knownhosts"
+)
+
+// Dialer dials broker addresses through a pooled SSH tunnel. One ssh.Client
+// is kept per SSH endpoint and reused for every broker connection opened
+// through it; a dead client is dropped and reconnected on the next dial.
+type Dialer struct {
+	cfg config.SSHConfig
+
+	mu      sync.Mutex
+	client  *ssh.Client
+	dialing chan struct{}
+}
+
+// NewDialer builds a Dialer from the given SSH configuration. The
+// configuration is assumed to have passed config.Config.Validate().
+func NewDialer(cfg config.SSHConfig) (*Dialer, error) {
+	if !cfg.Enable {
+		return nil, errors.New("sshdialer: SSH config is not enabled")
+	}
+	return &Dialer{cfg: cfg}, nil
+}
+
+// DialContext opens a connection to address through the SSH tunnel,
+// establishing (or reusing) the underlying ssh.Client to cfg.Host.
+func (d *Dialer) DialContext(ctx context.Context, network, address string) (net.Conn, error) {
+	client, err := d.getClient()
+	if err != nil {
+		return nil, err
+	}
+	conn, err := client.DialContext(ctx, "tcp", address)
+	if err != nil {
+		d.dropClient(client)
+		return nil, errors.Wrapf(err, "sshdialer: dial %s through %s", address, d.cfg.Host)
+	}
+	return conn, nil
+}
+
+// Close releases the pooled ssh.Client, if one is currently open.
+func (d *Dialer) Close() error {
+	d.mu.Lock()
+	client := d.client
+	d.client = nil
+	d.mu.Unlock()
+	if client == nil {
+		return nil
+	}
+	return client.Close()
+}
+
+// dropClient removes client from the pool, if it is still the current one,
+// and closes it so its connection and goroutines don't leak on reconnect.
+func (d *Dialer) dropClient(client *ssh.Client) {
+	d.mu.Lock()
+	if d.client == client {
+		d.client = nil
+	}
+	d.mu.Unlock()
+	_ = client.Close()
+}
+
+func (d *Dialer) getClient() (*ssh.Client, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.client != nil {
+		return d.client, nil
+	}
+
+	authMethods, err := authMethods(d.cfg)
+	if err != nil {
+		return nil, err
+	}
+	hostKeyCallback, err := hostKeyCallback(d.cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	sshConfig := &ssh.ClientConfig{
+		User:            d.cfg.User,
+		Auth:            authMethods,
+		HostKeyCallback: hostKeyCallback,
+		Timeout:         d.cfg.Timeout,
+	}
+	client, err := ssh.Dial("tcp", d.cfg.Host, sshConfig)
+	if err != nil {
+		return nil, errors.Wrapf(err, "sshdialer: connect to %s", d.cfg.Host)
+	}
+	if d.cfg.KeepAlive > 0 {
+		go d.keepAlive(client, d.cfg.KeepAlive)
+	}
+	d.client = client
+	return client, nil
+}
+
+func (d *Dialer) keepAlive(client *ssh.Client, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for range ticker.C {
+		if _, _, err := client.SendRequest("keepalive@grepplabs.com", true, nil); err != nil {
+			d.dropClient(client)
+			return
+		}
+	}
+}
+
+func authMethods(cfg config.SSHConfig) ([]ssh.AuthMethod, error) {
+	var methods []ssh.AuthMethod
+
+	keyPEM := []byte(cfg.PrivateKey)
+	if cfg.PrivateKeyPath != "" {
+		contents, err := ioutil.ReadFile(cfg.PrivateKeyPath)
+		if err != nil {
+			return nil, errors.Wrap(err, "sshdialer: reading PrivateKeyPath")
+		}
+		keyPEM = contents
+	}
+	if len(keyPEM) > 0 {
+		var signer ssh.Signer
+		var err error
+		if cfg.Passphrase != "" {
+			signer, err = ssh.ParsePrivateKeyWithPassphrase(keyPEM, []byte(cfg.Passphrase))
+		} else {
+			signer, err = ssh.ParsePrivateKey(keyPEM)
+		}
+		if err != nil {
+			return nil, errors.Wrap(err, "sshdialer: parsing private key")
+		}
+		methods = append(methods, ssh.PublicKeys(signer))
+	}
+	if cfg.Password != "" {
+		methods = append(methods, ssh.Password(cfg.Password))
+	}
+	if len(methods) == 0 {
+		return nil, errors.New("sshdialer: no SSH authentication method configured")
+	}
+	return methods, nil
+}
+
+func hostKeyCallback(cfg config.SSHConfig) (ssh.HostKeyCallback, error) {
+	if cfg.InsecureIgnoreHostKey {
+		return ssh.InsecureIgnoreHostKey(), nil
+	}
+	callback, err := knownhosts.New(cfg.KnownHostsFile)
+	if err != nil {
+		return nil, errors.Wrap(err, "sshdialer: reading KnownHostsFile")
+	}
+	return callback, nil
+}
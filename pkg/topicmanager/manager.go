@@ -0,0 +1,152 @@
+package topicmanager
+
+import (
+	"sync"
+	"time"
+
+	"github.com/Shopify/sarama"
+	"github.com/grepplabs/kafka-proxy/config"
+	"github.com/pkg/errors"
+)
+
+const defaultMetadataRefreshInterval = 10 * time.Minute
+
+// cacheEntry holds the topic metadata known to the proxy together with the
+// time it was last fetched from the cluster, so that bursts of client
+// Metadata requests coalesce into a single admin call.
+type cacheEntry struct {
+	metadata    *sarama.TopicDetail
+	lastRefresh time.Time
+}
+
+// Manager auto-creates topics referenced by a client Produce/Metadata
+// request that don't yet exist on the cluster, and caches topic metadata
+// refreshed on a ticker so concurrent clients don't hammer the cluster.
+type Manager struct {
+	cfg   config.AdminConfig
+	admin sarama.ClusterAdmin
+
+	cache sync.Map // topic name -> *cacheEntry
+
+	minRefreshInterval time.Duration
+
+	stop chan struct{}
+}
+
+// NewManager opens a sarama ClusterAdmin against bootstrapServers and starts
+// the background metadata refresh ticker. Close must be called to release
+// the admin client.
+func NewManager(cfg config.AdminConfig, bootstrapServers []string, saramaCfg *sarama.Config) (*Manager, error) {
+	admin, err := sarama.NewClusterAdmin(bootstrapServers, saramaCfg)
+	if err != nil {
+		return nil, errors.Wrap(err, "topicmanager: creating cluster admin")
+	}
+
+	refreshInterval := cfg.MetadataRefreshInterval
+	if refreshInterval <= 0 {
+		refreshInterval = defaultMetadataRefreshInterval
+	}
+
+	m := &Manager{
+		cfg:                cfg,
+		admin:              admin,
+		minRefreshInterval: refreshInterval,
+		stop:               make(chan struct{}),
+	}
+	go m.refreshLoop(refreshInterval)
+	return m, nil
+}
+
+// Close stops the refresh loop and closes the underlying ClusterAdmin.
+func (m *Manager) Close() error {
+	close(m.stop)
+	return m.admin.Close()
+}
+
+// EnsureTopic creates topic with the configured defaults if it does not
+// already exist on the cluster. It is a no-op when AutoCreateTopics is
+// disabled or the topic is already known to the cache.
+func (m *Manager) EnsureTopic(topic string) error {
+	if !m.cfg.AutoCreateTopics {
+		return nil
+	}
+	if _, ok := m.cache.Load(topic); ok {
+		return nil
+	}
+
+	detail := &sarama.TopicDetail{
+		NumPartitions:     m.cfg.DefaultPartitions,
+		ReplicationFactor: m.cfg.DefaultReplicationFactor,
+		ConfigEntries:     stringPtrMap(m.cfg.TopicConfigOverrides[topic]),
+	}
+	err := m.admin.CreateTopic(topic, detail, false)
+	if err != nil && !isTopicExistsError(err) {
+		return errors.Wrapf(err, "topicmanager: creating topic %s", topic)
+	}
+	m.cache.Store(topic, &cacheEntry{metadata: detail, lastRefresh: time.Now()})
+	return nil
+}
+
+// Metadata returns the cached TopicDetail for topic, refreshing it from the
+// cluster first if the cache entry is stale by more than the manager's
+// minimum refresh interval.
+func (m *Manager) Metadata(topic string) (*sarama.TopicDetail, error) {
+	if entry, ok := m.cache.Load(topic); ok {
+		e := entry.(*cacheEntry)
+		if time.Since(e.lastRefresh) < m.minRefreshInterval {
+			return e.metadata, nil
+		}
+	}
+	return m.refreshTopic(topic)
+}
+
+func (m *Manager) refreshLoop(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-m.stop:
+			return
+		case <-ticker.C:
+			m.cache.Range(func(key, _ interface{}) bool {
+				_, _ = m.refreshTopic(key.(string))
+				return true
+			})
+		}
+	}
+}
+
+func (m *Manager) refreshTopic(topic string) (*sarama.TopicDetail, error) {
+	topics, err := m.admin.DescribeTopics([]string{topic})
+	if err != nil {
+		return nil, errors.Wrapf(err, "topicmanager: describing topic %s", topic)
+	}
+	// DescribeTopics always returns one TopicMetadata per requested topic,
+	// even when it does not exist: Err is set and Partitions is empty.
+	if len(topics) == 0 || topics[0].Err != sarama.ErrNoError || len(topics[0].Partitions) == 0 {
+		return nil, errors.Errorf("topicmanager: topic %s not found", topic)
+	}
+	detail := &sarama.TopicDetail{
+		NumPartitions:     int32(len(topics[0].Partitions)),
+		ReplicationFactor: int16(len(topics[0].Partitions[0].Replicas)),
+	}
+	m.cache.Store(topic, &cacheEntry{metadata: detail, lastRefresh: time.Now()})
+	return detail, nil
+}
+
+func stringPtrMap(overrides map[string]string) map[string]*string {
+	if len(overrides) == 0 {
+		return nil
+	}
+	entries := make(map[string]*string, len(overrides))
+	for k, v := range overrides {
+		v := v
+		entries[k] = &v
+	}
+	return entries
+}
+
+func isTopicExistsError(err error) bool {
+	kErr, ok := err.(*sarama.TopicError)
+	return ok && kErr.Err == sarama.ErrTopicAlreadyExists
+}
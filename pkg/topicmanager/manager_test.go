@@ -0,0 +1,61 @@
+package topicmanager
+
+import (
+	"testing"
+
+	"github.com/Shopify/sarama"
+)
+
+// fakeClusterAdmin implements sarama.ClusterAdmin, delegating every method
+// the tests don't care about to the embedded nil interface (it panics if
+// called, which immediately flags a test exercising untested behaviour).
+type fakeClusterAdmin struct {
+	sarama.ClusterAdmin
+	describeTopicsResponse []*sarama.TopicMetadata
+}
+
+func (f *fakeClusterAdmin) DescribeTopics(topics []string) ([]*sarama.TopicMetadata, error) {
+	return f.describeTopicsResponse, nil
+}
+
+func TestRefreshTopic_UnknownTopicDoesNotPanic(t *testing.T) {
+	m := &Manager{
+		admin: &fakeClusterAdmin{
+			describeTopicsResponse: []*sarama.TopicMetadata{
+				{Name: "missing-topic", Err: sarama.ErrUnknownTopicOrPartition, Partitions: nil},
+			},
+		},
+	}
+
+	_, err := m.refreshTopic("missing-topic")
+	if err == nil {
+		t.Fatal("expected an error for an unknown topic, got nil")
+	}
+}
+
+func TestRefreshTopic_KnownTopic(t *testing.T) {
+	m := &Manager{
+		admin: &fakeClusterAdmin{
+			describeTopicsResponse: []*sarama.TopicMetadata{
+				{
+					Name: "known-topic",
+					Err:  sarama.ErrNoError,
+					Partitions: []*sarama.PartitionMetadata{
+						{ID: 0, Replicas: []int32{1, 2, 3}},
+					},
+				},
+			},
+		},
+	}
+
+	detail, err := m.refreshTopic("known-topic")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if detail.NumPartitions != 1 {
+		t.Errorf("expected 1 partition, got %d", detail.NumPartitions)
+	}
+	if detail.ReplicationFactor != 3 {
+		t.Errorf("expected replication factor 3, got %d", detail.ReplicationFactor)
+	}
+}